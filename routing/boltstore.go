@@ -0,0 +1,113 @@
+package routing
+
+import (
+	"bytes"
+	"encoding/gob"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+const boltNodesBucket = "nodes"
+
+/*
+  BoltStore is an InMemoryStore whose node/measurement map is mirrored to a
+  BoltDB file, so state survives a restart. Subscriptions, tracking and
+  history are still purely in-memory, same as InMemoryStore.
+*/
+type BoltStore struct {
+	*InMemoryStore
+	db *bolt.DB
+}
+
+func NewBoltStore(path string) (*BoltStore, error) {
+	db, err := bolt.Open(path, 0600, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists([]byte(boltNodesBucket))
+		return err
+	}); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	s := &BoltStore{InMemoryStore: NewInMemoryStore(), db: db}
+	if err := s.load(); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *BoltStore) load() error {
+	return s.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(boltNodesBucket))
+		return b.ForEach(func(k, v []byte) error {
+			var measurements map[string]Data
+			if err := gob.NewDecoder(bytes.NewReader(v)).Decode(&measurements); err != nil {
+				return err
+			}
+			datapoints := make([]Data, 0, len(measurements))
+			for _, d := range measurements {
+				datapoints = append(datapoints, d)
+			}
+			_, _ = s.InMemoryStore.Write(&Write{Node: string(k), Datapoints: datapoints})
+			return nil
+		})
+	})
+}
+
+func (s *BoltStore) Write(w *Write) (error, Reply) {
+	err, reply := s.InMemoryStore.Write(w)
+	if err != nil {
+		return err, reply
+	}
+	if err := s.persist(w.Node); err != nil {
+		return err, reply
+	}
+	return nil, reply
+}
+
+func (s *BoltStore) WriteAppend(w *Write) (error, Reply) {
+	err, reply := s.InMemoryStore.WriteAppend(w)
+	if err != nil {
+		return err, reply
+	}
+	if err := s.persist(w.Node); err != nil {
+		return err, reply
+	}
+	return nil, reply
+}
+
+func (s *BoltStore) persist(node string) error {
+	s.mu.RLock()
+	measurements, found := s.datastore[node]
+	snapshot := make(map[string]Data, len(measurements))
+	for k, v := range measurements {
+		snapshot[k] = v
+	}
+	s.mu.RUnlock()
+	if !found {
+		return nil
+	}
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(snapshot); err != nil {
+		return err
+	}
+	return s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(boltNodesBucket))
+		return b.Put([]byte(node), buf.Bytes())
+	})
+}
+
+func (s *BoltStore) Close() error {
+	if err := s.InMemoryStore.Close(); err != nil {
+		return err
+	}
+	return s.db.Close()
+}
+
+var _ Datastore = (*BoltStore)(nil)
@@ -0,0 +1,38 @@
+package routing
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestBoltStorePersistsAcrossRestart(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bolt.db")
+
+	s1, err := NewBoltStore(path)
+	if err != nil {
+		t.Fatalf("NewBoltStore failed: %v", err)
+	}
+	if err, _ := s1.Write(&Write{Node: "node1", Datapoints: []Data{
+		{Measurement: "cpu", Value: "42", Timestamp: 100},
+	}}); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	if err := s1.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	s2, err := NewBoltStore(path)
+	if err != nil {
+		t.Fatalf("reopening NewBoltStore failed: %v", err)
+	}
+	defer s2.Close()
+
+	replyChan := make(chan Reply, 1)
+	if err, _ := s2.ReadImmediate(&Request{Node: "node1", Measurements: []string{"cpu"}, ReplyChan: replyChan}); err != nil {
+		t.Fatalf("ReadImmediate failed: %v", err)
+	}
+	reply := <-replyChan
+	if len(reply.Datapoints) != 1 || reply.Datapoints[0].Value != "42" {
+		t.Fatalf("expected persisted datapoint to survive restart, got %+v", reply.Datapoints)
+	}
+}
@@ -0,0 +1,200 @@
+package routing
+
+import (
+	"bytes"
+	"encoding/gob"
+	"fmt"
+	"strings"
+	"time"
+
+	consulapi "github.com/hashicorp/consul/api"
+)
+
+const defaultConsulPrefix = "nodes"
+
+/*
+  ConsulStore fans writes out to a Consul KV prefix (nodes/<node>/<measurement>)
+  and runs a blocking-query watch over that prefix to drive publish, so
+  subscribers see updates regardless of which node in the cluster performed
+  the Write.
+*/
+type ConsulStore struct {
+	*InMemoryStore
+	client    *consulapi.Client
+	prefix    string
+	stopWatch chan struct{}
+}
+
+func NewConsulStore(cfg *consulapi.Config) (*ConsulStore, error) {
+	client, err := consulapi.NewClient(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &ConsulStore{
+		InMemoryStore: NewInMemoryStore(),
+		client:        client,
+		prefix:        defaultConsulPrefix,
+		stopWatch:     make(chan struct{}),
+	}
+	go s.watch()
+	return s, nil
+}
+
+// Write upserts w's datapoints under the node's KV prefix and, like
+// InMemoryStore.Write, deletes any measurement previously stored for the
+// node that is absent from w.Datapoints, so the "delete measurements not
+// present" contract documented on Write holds regardless of backend.
+func (s *ConsulStore) Write(w *Write) (error, Reply) {
+	kv := s.client.KV()
+
+	present := make(map[string]struct{}, len(w.Datapoints))
+	for _, d := range w.Datapoints {
+		present[d.Measurement] = struct{}{}
+	}
+
+	existing, _, err := kv.List(s.nodePrefix(w.Node), nil)
+	if err != nil {
+		return err, Reply{}
+	}
+	for _, pair := range existing {
+		_, measurement, ok := splitConsulKey(s.prefix, pair.Key)
+		if !ok {
+			continue
+		}
+		if _, ok := present[measurement]; ok {
+			continue
+		}
+		if _, err := kv.Delete(pair.Key, nil); err != nil {
+			return err, Reply{}
+		}
+	}
+
+	for _, d := range w.Datapoints {
+		var buf bytes.Buffer
+		if err := gob.NewEncoder(&buf).Encode(d); err != nil {
+			return err, Reply{}
+		}
+		pair := &consulapi.KVPair{Key: s.key(w.Node, d.Measurement), Value: buf.Bytes()}
+		if _, err := kv.Put(pair, nil); err != nil {
+			return err, Reply{}
+		}
+	}
+	return nil, Reply{RequestId: s.requestId()}
+}
+
+// WriteAppend upserts w's datapoints under the node's KV prefix like
+// Write, but leaves the node's other measurements in place.
+func (s *ConsulStore) WriteAppend(w *Write) (error, Reply) {
+	kv := s.client.KV()
+	for _, d := range w.Datapoints {
+		var buf bytes.Buffer
+		if err := gob.NewEncoder(&buf).Encode(d); err != nil {
+			return err, Reply{}
+		}
+		pair := &consulapi.KVPair{Key: s.key(w.Node, d.Measurement), Value: buf.Bytes()}
+		if _, err := kv.Put(pair, nil); err != nil {
+			return err, Reply{}
+		}
+	}
+	return nil, Reply{RequestId: s.requestId()}
+}
+
+// watch runs blocking queries against the KV prefix and applies every
+// change it observes to the local InMemoryStore, which is what actually
+// fires publish for subscribers. This is the only path data takes into
+// the local store, so a Write from any node in the cluster ends up
+// publishing here the same way.
+func (s *ConsulStore) watch() {
+	kv := s.client.KV()
+	var lastIndex uint64
+
+	for {
+		select {
+		case <-s.stopWatch:
+			return
+		default:
+		}
+
+		pairs, meta, err := kv.List(s.prefix, &consulapi.QueryOptions{WaitIndex: lastIndex})
+		if err != nil {
+			time.Sleep(time.Second)
+			continue
+		}
+		lastIndex = meta.LastIndex
+
+		seen := make(map[Key]struct{}, len(pairs))
+		for _, pair := range pairs {
+			node, measurement, ok := splitConsulKey(s.prefix, pair.Key)
+			if !ok {
+				continue
+			}
+			seen[Key{Node: node, Measurement: measurement}] = struct{}{}
+			var d Data
+			if err := gob.NewDecoder(bytes.NewReader(pair.Value)).Decode(&d); err != nil {
+				continue
+			}
+			d.Measurement = measurement
+			s.applyRemote(node, d)
+		}
+
+		// Measurements whose keys are gone from the prefix were deleted
+		// by a Write somewhere in the cluster; drop them locally too.
+		s.mu.Lock()
+		for node, measurements := range s.datastore {
+			for measurement := range measurements {
+				if _, ok := seen[Key{Node: node, Measurement: measurement}]; !ok {
+					delete(measurements, measurement)
+				}
+			}
+		}
+		s.mu.Unlock()
+	}
+}
+
+// applyRemote upserts a single measurement without touching any other
+// measurement for the node, unlike Write, since each Consul key only ever
+// describes one measurement. It is the only path that applies a write
+// observed via the cluster (as opposed to a local Write call) to this
+// store's state, so it must fire both publish and publishSelectors --
+// otherwise a SubscribeSelector subscriber would never hear about writes
+// made on another node.
+func (s *ConsulStore) applyRemote(node string, d Data) {
+	s.mu.Lock()
+	nodeMap, found := s.datastore[node]
+	if !found {
+		nodeMap = make(map[string]Data)
+		s.datastore[node] = nodeMap
+	}
+	nodeMap[d.Measurement] = d
+	key := Key{Node: node, Measurement: d.Measurement}
+	s.appendHistory(key, d)
+	s.mu.Unlock()
+
+	s.publish(key, d.Value, d.Timestamp)
+	s.publishSelectors(key, d.Value, d.Timestamp)
+}
+
+func (s *ConsulStore) key(node, measurement string) string {
+	return fmt.Sprintf("%s/%s/%s", s.prefix, node, measurement)
+}
+
+func (s *ConsulStore) nodePrefix(node string) string {
+	return fmt.Sprintf("%s/%s/", s.prefix, node)
+}
+
+func splitConsulKey(prefix, key string) (node, measurement string, ok bool) {
+	trimmed := strings.TrimPrefix(key, prefix+"/")
+	parts := strings.SplitN(trimmed, "/", 2)
+	if len(parts) != 2 {
+		return "", "", false
+	}
+	return parts[0], parts[1], true
+}
+
+func (s *ConsulStore) Close() error {
+	close(s.stopWatch)
+	return s.InMemoryStore.Close()
+}
+
+var _ Datastore = (*ConsulStore)(nil)
@@ -0,0 +1,60 @@
+package routing
+
+import "testing"
+
+func TestConsulStoreKeyRoundTrip(t *testing.T) {
+	s := &ConsulStore{prefix: defaultConsulPrefix}
+
+	key := s.key("node1", "cpu.user")
+	node, measurement, ok := splitConsulKey(s.prefix, key)
+	if !ok {
+		t.Fatalf("splitConsulKey failed to parse %q", key)
+	}
+	if node != "node1" || measurement != "cpu.user" {
+		t.Fatalf("got node=%q measurement=%q, want node1/cpu.user", node, measurement)
+	}
+}
+
+func TestSplitConsulKeyRejectsMalformed(t *testing.T) {
+	if _, _, ok := splitConsulKey(defaultConsulPrefix, "nodes/node1"); ok {
+		t.Fatalf("expected a key with no measurement segment to be rejected")
+	}
+}
+
+func TestConsulStoreNodePrefix(t *testing.T) {
+	s := &ConsulStore{prefix: defaultConsulPrefix}
+
+	if got, want := s.nodePrefix("node1"), "nodes/node1/"; got != want {
+		t.Fatalf("nodePrefix() = %q, want %q", got, want)
+	}
+	_, _, ok := splitConsulKey(s.prefix, s.nodePrefix("node1")+"cpu")
+	if !ok {
+		t.Fatalf("keys under nodePrefix() should parse back with splitConsulKey")
+	}
+}
+
+// applyRemote is exercised directly, without a live Consul client, since
+// it only touches the embedded InMemoryStore.
+func TestConsulStoreApplyRemoteNotifiesSelectors(t *testing.T) {
+	s := &ConsulStore{InMemoryStore: NewInMemoryStore(), prefix: defaultConsulPrefix}
+	write(t, s.InMemoryStore, "web-1", "cpu.load", "1", 100)
+
+	replyChan := make(chan Reply, 1)
+	if err, _ := s.SubscribeSelector(&Request{
+		Node:         "web-*",
+		Measurements: []string{"cpu.*"},
+		ReplyChan:    replyChan,
+	}); err != nil {
+		t.Fatalf("SubscribeSelector failed: %v", err)
+	}
+
+	s.applyRemote("web-1", Data{Measurement: "cpu.load", Value: "2", Timestamp: 101})
+	select {
+	case r := <-replyChan:
+		if r.Datapoints[0].Value != "2" {
+			t.Errorf("expected value 2, got %q", r.Datapoints[0].Value)
+		}
+	default:
+		t.Fatal("expected applyRemote to notify the selector subscriber")
+	}
+}
@@ -3,14 +3,15 @@ package routing
 import (
 	"errors"
 	"fmt"
+	"os"
 	"sync"
 	"sync/atomic"
 	"time"
 )
 
 /*
-  This is the InMemory store which will store only the latest available data.
-  To store historical data also, change datastore to []Data and adjust some queries
+  This is the InMemory store which stores the latest available data plus
+  a bounded ring-buffer history per Key, queryable with ReadRange.
 */
 type Tracking struct {
 	requestId string
@@ -22,9 +23,16 @@ type Key struct {
 	Node, Measurement string
 }
 
+// Default history settings used by NewInMemoryStore.
+const (
+	defaultHistoryCapacity = 360
+	defaultHistorySlack    = time.Minute
+)
+
 type InMemoryStore struct {
 	mu        sync.RWMutex
 	datastore map[string]map[string]Data
+	history   map[Key]*history
 
 	trackMu  sync.Mutex
 	tracking map[string]*Tracking
@@ -32,18 +40,66 @@ type InMemoryStore struct {
 	subsMu       sync.RWMutex
 	subscription map[Key][]*Tracking
 
+	selectorMu sync.RWMutex
+	selectors  map[string]*SelectorTracking
+
 	nextId int64
+
+	historyCapacity int
+	slack           time.Duration
+	retention       time.Duration
+	stopSweep       chan struct{}
+
+	walFile      *os.File
+	walPath      string
+	snapshotPath string
+	stopCompact  chan struct{}
 }
 
 // Methods required by the Datastore interface
 func NewInMemoryStore() *InMemoryStore {
+	return NewInMemoryStoreWithHistory(defaultHistoryCapacity, 0, defaultHistorySlack)
+}
+
+// NewInMemoryStoreWithHistory is like NewInMemoryStore but lets the caller
+// configure the per-key history capacity, an optional retention window
+// (samples older than the window are swept away; 0 disables the sweeper)
+// and the out-of-order slack tolerated before a write is dropped from
+// history.
+func NewInMemoryStoreWithHistory(capacity int, retention, slack time.Duration) *InMemoryStore {
 	m := new(InMemoryStore)
 	m.datastore = make(map[string]map[string]Data)
+	m.history = make(map[Key]*history)
 	m.tracking = make(map[string]*Tracking)
 	m.subscription = make(map[Key][]*Tracking)
+	m.selectors = make(map[string]*SelectorTracking)
+	m.historyCapacity = capacity
+	m.slack = slack
+
+	if retention > 0 {
+		m.retention = retention
+		m.stopSweep = make(chan struct{})
+		go m.sweepLoop()
+	}
 	return m
 }
 
+// Close stops any background goroutines the store started (the retention
+// sweeper, the WAL compactor) and closes the WAL file, if one is open. It
+// is safe to call on a store created without either.
+func (m *InMemoryStore) Close() error {
+	if m.stopSweep != nil {
+		close(m.stopSweep)
+	}
+	if m.stopCompact != nil {
+		close(m.stopCompact)
+	}
+	if m.walFile != nil {
+		return m.walFile.Close()
+	}
+	return nil
+}
+
 /*
   Equals subscription request..
 */
@@ -98,13 +154,56 @@ func (m *InMemoryStore) ReadImmediate(r *Request) (error, Reply) {
 }
 
 func (m *InMemoryStore) Write(w *Write) (error, Reply) {
-	datapoints := make(map[string]struct{})
-	for _, d := range w.Datapoints {
-		datapoints[d.Measurement] = struct{}{}
+	normalizeTimestamps(w)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := m.appendWALLocked(w, true); err != nil {
+		return err, Reply{}
 	}
+	return nil, m.applyWriteLocked(w, true)
+}
 
+// WriteAppend writes w's datapoints like Write, but skips the "delete
+// measurements not present in this call" pass, for incremental ingestion
+// (e.g. batches decoded from a line-protocol stream) where a single
+// request isn't expected to describe a node's full measurement set.
+func (m *InMemoryStore) WriteAppend(w *Write) (error, Reply) {
+	normalizeTimestamps(w)
 	m.mu.Lock()
 	defer m.mu.Unlock()
+	if err := m.appendWALLocked(w, false); err != nil {
+		return err, Reply{}
+	}
+	return nil, m.applyWriteLocked(w, false)
+}
+
+// normalizeTimestamps fills in the current time for any datapoint that
+// omitted Timestamp, before the write reaches the WAL or the in-memory
+// map, so the record persisted to disk and the value applied to the
+// store never disagree.
+func normalizeTimestamps(w *Write) {
+	for i := range w.Datapoints {
+		if w.Datapoints[i].Timestamp < 1 {
+			w.Datapoints[i].Timestamp = time.Now().Unix()
+		}
+	}
+}
+
+// applyWrite is applyWriteLocked for callers (replayWAL) that don't
+// already hold m.mu.
+func (m *InMemoryStore) applyWrite(w *Write, deleteAbsent bool) (error, Reply) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return nil, m.applyWriteLocked(w, deleteAbsent)
+}
+
+// applyWriteLocked mutates the in-memory map and fires publish/
+// publishSelectors for w. The caller must hold m.mu.
+func (m *InMemoryStore) applyWriteLocked(w *Write, deleteAbsent bool) Reply {
+	datapoints := make(map[string]struct{})
+	for _, d := range w.Datapoints {
+		datapoints[d.Measurement] = struct{}{}
+	}
 
 	// Check that we have node registered..
 	node, found := m.datastore[w.Node]
@@ -113,24 +212,86 @@ func (m *InMemoryStore) Write(w *Write) (error, Reply) {
 		node = m.datastore[w.Node]
 	}
 
-	for _, datas := range node {
-		if _, ok := datapoints[datas.Measurement]; !ok {
-			delete(m.subscription, Key{Node: w.Node, Measurement: datas.Measurement})
-			delete(m.datastore[w.Node], datas.Measurement)
+	if deleteAbsent {
+		for _, datas := range node {
+			if _, ok := datapoints[datas.Measurement]; !ok {
+				delete(m.subscription, Key{Node: w.Node, Measurement: datas.Measurement})
+				delete(m.datastore[w.Node], datas.Measurement)
+			}
 		}
 	}
 
 	for _, data := range w.Datapoints {
 		if data.Timestamp < 1 {
-			// Add current timestamp if none was given
+			// Callers are expected to go through Write/WriteAppend, which
+			// normalize this before the WAL sees it; this is only a
+			// fallback for direct applyWriteLocked callers.
 			data.Timestamp = time.Now().Unix()
 		}
 		node[data.Measurement] = data
 		trackKey := Key{Node: w.Node, Measurement: data.Measurement}
+		m.appendHistory(trackKey, data)
 		m.publish(trackKey, data.Value, data.Timestamp)
+		m.publishSelectors(trackKey, data.Value, data.Timestamp)
 	}
 
-	return nil, Reply{RequestId: m.requestId()}
+	return Reply{RequestId: m.requestId()}
+}
+
+// appendHistory records data in the ring buffer for key, rejecting it as
+// out-of-order if it is older than the newest sample by more than the
+// configured slack. The latest-value store is unaffected either way.
+func (m *InMemoryStore) appendHistory(key Key, data Data) {
+	h, found := m.history[key]
+	if !found {
+		h = newHistory(m.historyCapacity)
+		m.history[key] = h
+	}
+
+	if newest, ok := h.newest(); ok {
+		slackSeconds := int64(m.slack.Seconds())
+		if data.Timestamp < newest.Timestamp-slackSeconds {
+			return
+		}
+	}
+	h.append(data)
+}
+
+/*
+  ReadRange returns every sample for r.Measurements on r.Node whose
+  Timestamp falls in [r.From, r.To], ascending, via r.ReplyChan. r.Limit
+  caps the number of samples returned per measurement (0 means unlimited).
+*/
+func (m *InMemoryStore) ReadRange(r *Request) (error, Reply) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if _, found := m.datastore[r.Node]; !found {
+		return errors.New("Could not find requested information"), Reply{}
+	}
+
+	data := make([]Data, 0, 10)
+	for _, me := range r.Measurements {
+		h, found := m.history[Key{Node: r.Node, Measurement: me}]
+		if !found {
+			continue
+		}
+		count := 0
+		for _, d := range h.ascending() {
+			if d.Timestamp < r.From || d.Timestamp > r.To {
+				continue
+			}
+			data = append(data, d)
+			count++
+			if r.Limit > 0 && count >= r.Limit {
+				break
+			}
+		}
+	}
+
+	reply := Reply{Node: r.Node, Datapoints: data, From: r.From, To: r.To, Limit: r.Limit}
+	r.ReplyChan <- reply
+	return nil, Reply{}
 }
 
 func (m *InMemoryStore) Cancel(requestId string) error {
@@ -153,7 +314,17 @@ func (m *InMemoryStore) Cancel(requestId string) error {
 			}
 		*/
 		delete(m.tracking, requestId)
-	} else {
+		return nil
+	}
+
+	m.selectorMu.Lock()
+	_, found := m.selectors[requestId]
+	if found {
+		delete(m.selectors, requestId)
+	}
+	m.selectorMu.Unlock()
+
+	if !found {
 		return errors.New("No subscription found for " + requestId)
 	}
 
@@ -180,6 +351,36 @@ func (m *InMemoryStore) publish(key Key, value string, timestamp int64) error {
 	return nil
 }
 
+// sweepLoop periodically trims samples older than the retention window
+// from every key's history, until Close is called.
+func (m *InMemoryStore) sweepLoop() {
+	interval := m.retention / 10
+	if interval < 100*time.Millisecond {
+		interval = 100 * time.Millisecond
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			m.sweep()
+		case <-m.stopSweep:
+			return
+		}
+	}
+}
+
+func (m *InMemoryStore) sweep() {
+	cutoff := time.Now().Add(-m.retention).Unix()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, h := range m.history {
+		h.trimOlderThan(cutoff)
+	}
+}
+
 func keymap(dataslice []Data) map[string]struct{} {
 	var datapoints map[string]struct{} = make(map[string]struct{})
 	for _, d := range dataslice {
@@ -0,0 +1,106 @@
+package routing
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func write(t *testing.T, m *InMemoryStore, node, measurement string, value string, timestamp int64) {
+	t.Helper()
+	err, _ := m.Write(&Write{Node: node, Datapoints: []Data{
+		{Measurement: measurement, Value: value, Timestamp: timestamp},
+	}})
+	if err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+}
+
+func readRange(t *testing.T, m *InMemoryStore, node, measurement string, from, to int64) []Data {
+	t.Helper()
+	replyChan := make(chan Reply, 1)
+	err, _ := m.ReadRange(&Request{
+		Node:         node,
+		Measurements: []string{measurement},
+		From:         from,
+		To:           to,
+		ReplyChan:    replyChan,
+	})
+	if err != nil {
+		t.Fatalf("ReadRange failed: %v", err)
+	}
+	return (<-replyChan).Datapoints
+}
+
+func TestHistoryWrapAround(t *testing.T) {
+	m := NewInMemoryStoreWithHistory(3, 0, time.Hour)
+
+	for i := int64(1); i <= 5; i++ {
+		write(t, m, "node1", "cpu", "v", i)
+	}
+
+	got := readRange(t, m, "node1", "cpu", 0, 100)
+	if len(got) != 3 {
+		t.Fatalf("expected 3 samples after wrap-around, got %d", len(got))
+	}
+	for i, want := range []int64{3, 4, 5} {
+		if got[i].Timestamp != want {
+			t.Errorf("sample %d: expected timestamp %d, got %d", i, want, got[i].Timestamp)
+		}
+	}
+}
+
+func TestHistoryRetentionEviction(t *testing.T) {
+	m := NewInMemoryStoreWithHistory(100, 2*time.Second, time.Hour)
+	defer m.Close()
+
+	now := time.Now().Unix()
+	write(t, m, "node1", "cpu", "old", now-1000)
+	write(t, m, "node1", "cpu", "new", now)
+
+	time.Sleep(500 * time.Millisecond)
+
+	got := readRange(t, m, "node1", "cpu", 0, now+1)
+	if len(got) != 1 {
+		t.Fatalf("expected only the fresh sample to survive retention, got %d", len(got))
+	}
+	if got[0].Value != "new" {
+		t.Errorf("expected surviving sample to be the fresh one, got %q", got[0].Value)
+	}
+}
+
+func TestHistoryOutOfOrderSlack(t *testing.T) {
+	m := NewInMemoryStoreWithHistory(10, 0, 10*time.Second)
+
+	write(t, m, "node1", "cpu", "v1", 100)
+	write(t, m, "node1", "cpu", "too-old", 50)
+	write(t, m, "node1", "cpu", "v2", 105)
+
+	got := readRange(t, m, "node1", "cpu", 0, 1000)
+	if len(got) != 2 {
+		t.Fatalf("expected out-of-order sample beyond slack to be dropped, got %d samples", len(got))
+	}
+}
+
+func TestHistoryConcurrentReadersDuringEviction(t *testing.T) {
+	m := NewInMemoryStoreWithHistory(50, 50*time.Millisecond, time.Hour)
+	defer m.Close()
+
+	now := time.Now().Unix()
+	for i := int64(0); i < 50; i++ {
+		write(t, m, "node1", "cpu", "v", now-int64(i))
+	}
+
+	var wg sync.WaitGroup
+	stop := time.Now().Add(300 * time.Millisecond)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for time.Now().Before(stop) {
+				readRange(t, m, "node1", "cpu", 0, now+1)
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,201 @@
+package routing
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"io"
+	"os"
+	"time"
+)
+
+const compactInterval = 5 * time.Minute
+
+// NewInMemoryStoreWithWAL is like NewInMemoryStore, but every Write/
+// WriteAppend first appends a length-prefixed record to the write-ahead
+// log at path before mutating the in-memory map, and a background
+// compactor periodically snapshots the store to path+".snapshot" and
+// truncates the log. On startup it loads the last snapshot, if any, then
+// replays the log on top of it. Subscriptions are not persisted.
+func NewInMemoryStoreWithWAL(path string) (*InMemoryStore, error) {
+	m := NewInMemoryStore()
+	m.walPath = path
+	m.snapshotPath = path + ".snapshot"
+
+	if err := m.restoreFromDisk(); err != nil {
+		return nil, err
+	}
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, err
+	}
+	m.walFile = f
+
+	m.stopCompact = make(chan struct{})
+	go m.compactLoop()
+	return m, nil
+}
+
+// Snapshot serializes the node/measurement map to w. Subscriptions and
+// tracking state are not included -- channels can't survive a restart.
+func (m *InMemoryStore) Snapshot(w io.Writer) error {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return gob.NewEncoder(w).Encode(m.datastore)
+}
+
+// Restore replaces the node/measurement map with the contents read from
+// r, as produced by Snapshot, and rebuilds history from it.
+func (m *InMemoryStore) Restore(r io.Reader) error {
+	var datastore map[string]map[string]Data
+	if err := gob.NewDecoder(r).Decode(&datastore); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.datastore = datastore
+	m.history = make(map[Key]*history)
+	for node, measurements := range datastore {
+		for _, d := range measurements {
+			m.appendHistory(Key{Node: node, Measurement: d.Measurement}, d)
+		}
+	}
+	return nil
+}
+
+func (m *InMemoryStore) restoreFromDisk() error {
+	if snap, err := os.Open(m.snapshotPath); err == nil {
+		err := m.Restore(snap)
+		snap.Close()
+		if err != nil {
+			return err
+		}
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+
+	wal, err := os.Open(m.walPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	defer wal.Close()
+	return m.replayWAL(wal)
+}
+
+func (m *InMemoryStore) replayWAL(r io.Reader) error {
+	br := bufio.NewReader(r)
+	for {
+		var length [4]byte
+		if _, err := io.ReadFull(br, length[:]); err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+
+		record := make([]byte, binary.BigEndian.Uint32(length[:]))
+		if _, err := io.ReadFull(br, record); err != nil {
+			return err
+		}
+
+		var rec walRecord
+		if err := gob.NewDecoder(bytes.NewReader(record)).Decode(&rec); err != nil {
+			return err
+		}
+		if err, _ := m.applyWrite(&rec.Write, rec.DeleteAbsent); err != nil {
+			return err
+		}
+	}
+}
+
+// walRecord is the unit persisted to the WAL: a Write together with the
+// delete-absent semantics it was applied with (true for Write, false for
+// WriteAppend). Without it, replay would have to guess which op produced
+// the record and could silently drop measurements that WriteAppend never
+// meant to delete.
+type walRecord struct {
+	Write        Write
+	DeleteAbsent bool
+}
+
+// appendWALLocked appends w to the WAL file, if one is open, tagged with
+// the delete-absent semantics it was written with so replayWAL can apply
+// it the same way. The caller must hold m.mu for the rest of the write
+// too, so that a concurrent compact can never observe the WAL record
+// without the corresponding in-memory mutation, or vice versa.
+func (m *InMemoryStore) appendWALLocked(w *Write, deleteAbsent bool) error {
+	if m.walFile == nil {
+		return nil
+	}
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(walRecord{Write: *w, DeleteAbsent: deleteAbsent}); err != nil {
+		return err
+	}
+
+	var length [4]byte
+	binary.BigEndian.PutUint32(length[:], uint32(buf.Len()))
+	if _, err := m.walFile.Write(length[:]); err != nil {
+		return err
+	}
+	if _, err := m.walFile.Write(buf.Bytes()); err != nil {
+		return err
+	}
+	return m.walFile.Sync()
+}
+
+func (m *InMemoryStore) compactLoop() {
+	ticker := time.NewTicker(compactInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			m.compact()
+		case <-m.stopCompact:
+			return
+		}
+	}
+}
+
+// compact snapshots the store to disk and truncates the WAL. The snapshot
+// and the truncate run under a single m.mu critical section so that a
+// concurrent Write either lands entirely before the snapshot (and is thus
+// captured in it, safe to drop from the WAL) or entirely after the
+// truncate (and thus starts the new WAL from empty) -- never in the gap
+// between the two, where it would end up in neither. Errors are
+// swallowed; the next tick retries, and until it succeeds the WAL still
+// holds everything needed to reconstruct state.
+func (m *InMemoryStore) compact() error {
+	tmp := m.snapshotPath + ".tmp"
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	f, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	if err := gob.NewEncoder(f).Encode(m.datastore); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, m.snapshotPath); err != nil {
+		return err
+	}
+
+	if err := m.walFile.Truncate(0); err != nil {
+		return err
+	}
+	_, err = m.walFile.Seek(0, io.SeekStart)
+	return err
+}
@@ -0,0 +1,171 @@
+package routing
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+)
+
+func TestSnapshotRestoreRoundTrip(t *testing.T) {
+	m1 := NewInMemoryStore()
+	// Both datapoints must go through the same Write call: Write deletes
+	// any measurement previously stored for the node that is absent from
+	// the current call, so two separate write() calls for "node1" would
+	// leave only the second measurement behind.
+	if err, _ := m1.Write(&Write{Node: "node1", Datapoints: []Data{
+		{Measurement: "cpu", Value: "42", Timestamp: 100},
+		{Measurement: "mem", Value: "1024", Timestamp: 100},
+	}}); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := m1.Snapshot(&buf); err != nil {
+		t.Fatalf("Snapshot failed: %v", err)
+	}
+
+	m2 := NewInMemoryStore()
+	if err := m2.Restore(&buf); err != nil {
+		t.Fatalf("Restore failed: %v", err)
+	}
+
+	replyChan := make(chan Reply, 1)
+	if err, _ := m2.ReadImmediate(&Request{Node: "node1", Measurements: []string{"cpu", "mem"}, ReplyChan: replyChan}); err != nil {
+		t.Fatalf("ReadImmediate failed: %v", err)
+	}
+	reply := <-replyChan
+	if len(reply.Datapoints) != 2 {
+		t.Fatalf("expected 2 datapoints after restore, got %d", len(reply.Datapoints))
+	}
+}
+
+func TestWALReplaysAfterRestart(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "store.wal")
+
+	m1, err := NewInMemoryStoreWithWAL(path)
+	if err != nil {
+		t.Fatalf("NewInMemoryStoreWithWAL failed: %v", err)
+	}
+	write(t, m1, "node1", "cpu", "42", 100)
+	if err := m1.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	m2, err := NewInMemoryStoreWithWAL(path)
+	if err != nil {
+		t.Fatalf("reopening NewInMemoryStoreWithWAL failed: %v", err)
+	}
+	defer m2.Close()
+
+	replyChan := make(chan Reply, 1)
+	if err, _ := m2.ReadImmediate(&Request{Node: "node1", Measurements: []string{"cpu"}, ReplyChan: replyChan}); err != nil {
+		t.Fatalf("ReadImmediate failed: %v", err)
+	}
+	reply := <-replyChan
+	if len(reply.Datapoints) != 1 || reply.Datapoints[0].Value != "42" {
+		t.Fatalf("expected WAL-replayed datapoint, got %+v", reply.Datapoints)
+	}
+}
+
+func TestWALPersistsDefaultedTimestamp(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "store.wal")
+
+	m1, err := NewInMemoryStoreWithWAL(path)
+	if err != nil {
+		t.Fatalf("NewInMemoryStoreWithWAL failed: %v", err)
+	}
+	if err, reply := m1.Write(&Write{Node: "node1", Datapoints: []Data{
+		{Measurement: "cpu", Value: "42"},
+	}}); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	} else if reply.RequestId == "" {
+		t.Fatalf("expected a request id")
+	}
+
+	replyChan := make(chan Reply, 1)
+	if err, _ := m1.ReadImmediate(&Request{Node: "node1", Measurements: []string{"cpu"}, ReplyChan: replyChan}); err != nil {
+		t.Fatalf("ReadImmediate failed: %v", err)
+	}
+	want := (<-replyChan).Datapoints[0].Timestamp
+	if want == 0 {
+		t.Fatalf("expected Write to default the timestamp in memory")
+	}
+	if err := m1.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	m2, err := NewInMemoryStoreWithWAL(path)
+	if err != nil {
+		t.Fatalf("reopening NewInMemoryStoreWithWAL failed: %v", err)
+	}
+	defer m2.Close()
+
+	replyChan2 := make(chan Reply, 1)
+	if err, _ := m2.ReadImmediate(&Request{Node: "node1", Measurements: []string{"cpu"}, ReplyChan: replyChan2}); err != nil {
+		t.Fatalf("ReadImmediate failed: %v", err)
+	}
+	got := (<-replyChan2).Datapoints[0].Timestamp
+	if got != want {
+		t.Fatalf("replayed timestamp %d does not match the timestamp defaulted at write time %d", got, want)
+	}
+}
+
+func TestWALReplaysWriteAppendWithoutDeletingEarlierMeasurements(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "store.wal")
+
+	m1, err := NewInMemoryStoreWithWAL(path)
+	if err != nil {
+		t.Fatalf("NewInMemoryStoreWithWAL failed: %v", err)
+	}
+	if err, _ := m1.WriteAppend(&Write{Node: "node1", Datapoints: []Data{
+		{Measurement: "cpu", Value: "42", Timestamp: 100},
+	}}); err != nil {
+		t.Fatalf("WriteAppend failed: %v", err)
+	}
+	if err, _ := m1.WriteAppend(&Write{Node: "node1", Datapoints: []Data{
+		{Measurement: "mem", Value: "1024", Timestamp: 101},
+	}}); err != nil {
+		t.Fatalf("WriteAppend failed: %v", err)
+	}
+	if err := m1.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	m2, err := NewInMemoryStoreWithWAL(path)
+	if err != nil {
+		t.Fatalf("reopening NewInMemoryStoreWithWAL failed: %v", err)
+	}
+	defer m2.Close()
+
+	replyChan := make(chan Reply, 1)
+	if err, _ := m2.ReadImmediate(&Request{Node: "node1", Measurements: []string{"cpu", "mem"}, ReplyChan: replyChan}); err != nil {
+		t.Fatalf("ReadImmediate failed: %v", err)
+	}
+	reply := <-replyChan
+	if len(reply.Datapoints) != 2 {
+		t.Fatalf("expected both WriteAppend datapoints to survive replay, got %+v", reply.Datapoints)
+	}
+}
+
+func TestWALCompactTruncatesLog(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "store.wal")
+
+	m, err := NewInMemoryStoreWithWAL(path)
+	if err != nil {
+		t.Fatalf("NewInMemoryStoreWithWAL failed: %v", err)
+	}
+	defer m.Close()
+
+	write(t, m, "node1", "cpu", "42", 100)
+	if err := m.compact(); err != nil {
+		t.Fatalf("compact failed: %v", err)
+	}
+
+	info, err := m.walFile.Stat()
+	if err != nil {
+		t.Fatalf("Stat failed: %v", err)
+	}
+	if info.Size() != 0 {
+		t.Errorf("expected WAL truncated after compact, got size %d", info.Size())
+	}
+}
@@ -0,0 +1,109 @@
+package grpc
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+
+	"github.com/burmanm/core/routing"
+	pb "github.com/burmanm/core/routing/grpc/routingpb"
+)
+
+// Client mirrors the plain Go routing.InMemoryStore API over a gRPC
+// connection for Write, ReadImmediate, NodeList, SourceList and Cancel.
+// Subscribe differs: it takes a context and returns a channel of Replies
+// instead of delivering on r.ReplyChan, so Client does not satisfy
+// routing.Datastore.
+type Client struct {
+	conn *grpc.ClientConn
+	rpc  pb.RoutingServiceClient
+}
+
+func NewClient(target string, opts ...grpc.DialOption) (*Client, error) {
+	conn, err := grpc.Dial(target, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &Client{conn: conn, rpc: pb.NewRoutingServiceClient(conn)}, nil
+}
+
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+func (c *Client) Write(w *routing.Write) (error, routing.Reply) {
+	resp, err := c.rpc.Write(context.Background(), &pb.WriteRequest{
+		Node:       w.Node,
+		Datapoints: toDatapoints(w.Datapoints),
+	})
+	if err != nil {
+		return err, routing.Reply{}
+	}
+	return nil, routing.Reply{RequestId: resp.RequestId}
+}
+
+func (c *Client) ReadImmediate(r *routing.Request) (error, routing.Reply) {
+	resp, err := c.rpc.ReadImmediate(context.Background(), &pb.ReadRequest{
+		Node:         r.Node,
+		Measurements: r.Measurements,
+	})
+	if err != nil {
+		return err, routing.Reply{}
+	}
+	r.ReplyChan <- fromReadReply(resp)
+	return nil, routing.Reply{}
+}
+
+func (c *Client) NodeList() []string {
+	resp, err := c.rpc.NodeList(context.Background(), &pb.Empty{})
+	if err != nil {
+		return nil
+	}
+	return resp.Nodes
+}
+
+func (c *Client) SourceList(node string) (error, []string) {
+	resp, err := c.rpc.SourceList(context.Background(), &pb.SourceListRequest{Node: node})
+	if err != nil {
+		return err, nil
+	}
+	return nil, resp.Measurements
+}
+
+func (c *Client) Cancel(requestId string) error {
+	_, err := c.rpc.Cancel(context.Background(), &pb.CancelRequest{RequestId: requestId})
+	return err
+}
+
+// Subscribe streams Replies for r.Node/r.Measurements until ctx is
+// cancelled, at which point the returned channel is closed.
+func (c *Client) Subscribe(ctx context.Context, r *routing.Request) (<-chan routing.Reply, error) {
+	stream, err := c.rpc.Subscribe(ctx, &pb.SubscribeRequest{
+		Node:         r.Node,
+		Measurements: r.Measurements,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(chan routing.Reply, 16)
+	go func() {
+		defer close(out)
+		for {
+			resp, err := stream.Recv()
+			if err != nil {
+				return
+			}
+			out <- fromReadReply(resp)
+		}
+	}()
+	return out, nil
+}
+
+func fromReadReply(r *pb.ReadReply) routing.Reply {
+	return routing.Reply{
+		RequestId:  r.RequestId,
+		Node:       r.Node,
+		Datapoints: fromDatapoints(r.Datapoints),
+	}
+}
@@ -0,0 +1,15 @@
+/*
+  Package grpc exposes a routing.InMemoryStore (or anything satisfying the
+  same method set) over gRPC, and provides a client that mirrors the plain
+  Go API so callers can swap a remote store in transparently.
+
+  routingpb/routing.pb.go and routingpb/routing_grpc.pb.go mirror the
+  messages and service declared in routingpb/routing.proto but are
+  maintained by hand, not produced by protoc -- there's no protoc
+  toolchain wired up in this repo. If/when one is added, these can be
+  replaced by real `protoc --go_out=... --go-grpc_out=...` output against
+  routingpb/routing.proto; until then, edit the .proto and the two stub
+  files together.
+*/
+package grpc
+
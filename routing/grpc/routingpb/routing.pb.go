@@ -0,0 +1,239 @@
+// Hand-written to mirror the messages declared in routing.proto, in the
+// style of an older protoc-gen-go. There is no protoc toolchain wired up
+// in this repo yet, so these are maintained by hand alongside the .proto
+// -- keep them in sync when routing.proto changes.
+
+package routingpb
+
+import (
+	proto "github.com/golang/protobuf/proto"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+
+type Empty struct{}
+
+func (m *Empty) Reset()         { *m = Empty{} }
+func (m *Empty) String() string { return proto.CompactTextString(m) }
+func (*Empty) ProtoMessage()    {}
+
+type Datapoint struct {
+	Measurement string `protobuf:"bytes,1,opt,name=measurement,proto3" json:"measurement,omitempty"`
+	Value       string `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+	Timestamp   int64  `protobuf:"varint,3,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+}
+
+func (m *Datapoint) Reset()         { *m = Datapoint{} }
+func (m *Datapoint) String() string { return proto.CompactTextString(m) }
+func (*Datapoint) ProtoMessage()    {}
+
+func (m *Datapoint) GetMeasurement() string {
+	if m != nil {
+		return m.Measurement
+	}
+	return ""
+}
+
+func (m *Datapoint) GetValue() string {
+	if m != nil {
+		return m.Value
+	}
+	return ""
+}
+
+func (m *Datapoint) GetTimestamp() int64 {
+	if m != nil {
+		return m.Timestamp
+	}
+	return 0
+}
+
+type WriteRequest struct {
+	Node       string       `protobuf:"bytes,1,opt,name=node,proto3" json:"node,omitempty"`
+	Datapoints []*Datapoint `protobuf:"bytes,2,rep,name=datapoints,proto3" json:"datapoints,omitempty"`
+}
+
+func (m *WriteRequest) Reset()         { *m = WriteRequest{} }
+func (m *WriteRequest) String() string { return proto.CompactTextString(m) }
+func (*WriteRequest) ProtoMessage()    {}
+
+func (m *WriteRequest) GetNode() string {
+	if m != nil {
+		return m.Node
+	}
+	return ""
+}
+
+func (m *WriteRequest) GetDatapoints() []*Datapoint {
+	if m != nil {
+		return m.Datapoints
+	}
+	return nil
+}
+
+type WriteReply struct {
+	RequestId string `protobuf:"bytes,1,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+}
+
+func (m *WriteReply) Reset()         { *m = WriteReply{} }
+func (m *WriteReply) String() string { return proto.CompactTextString(m) }
+func (*WriteReply) ProtoMessage()    {}
+
+func (m *WriteReply) GetRequestId() string {
+	if m != nil {
+		return m.RequestId
+	}
+	return ""
+}
+
+type ReadRequest struct {
+	Node         string   `protobuf:"bytes,1,opt,name=node,proto3" json:"node,omitempty"`
+	Measurements []string `protobuf:"bytes,2,rep,name=measurements,proto3" json:"measurements,omitempty"`
+}
+
+func (m *ReadRequest) Reset()         { *m = ReadRequest{} }
+func (m *ReadRequest) String() string { return proto.CompactTextString(m) }
+func (*ReadRequest) ProtoMessage()    {}
+
+func (m *ReadRequest) GetNode() string {
+	if m != nil {
+		return m.Node
+	}
+	return ""
+}
+
+func (m *ReadRequest) GetMeasurements() []string {
+	if m != nil {
+		return m.Measurements
+	}
+	return nil
+}
+
+type ReadReply struct {
+	RequestId  string       `protobuf:"bytes,1,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	Node       string       `protobuf:"bytes,2,opt,name=node,proto3" json:"node,omitempty"`
+	Datapoints []*Datapoint `protobuf:"bytes,3,rep,name=datapoints,proto3" json:"datapoints,omitempty"`
+}
+
+func (m *ReadReply) Reset()         { *m = ReadReply{} }
+func (m *ReadReply) String() string { return proto.CompactTextString(m) }
+func (*ReadReply) ProtoMessage()    {}
+
+func (m *ReadReply) GetRequestId() string {
+	if m != nil {
+		return m.RequestId
+	}
+	return ""
+}
+
+func (m *ReadReply) GetNode() string {
+	if m != nil {
+		return m.Node
+	}
+	return ""
+}
+
+func (m *ReadReply) GetDatapoints() []*Datapoint {
+	if m != nil {
+		return m.Datapoints
+	}
+	return nil
+}
+
+type NodeListReply struct {
+	Nodes []string `protobuf:"bytes,1,rep,name=nodes,proto3" json:"nodes,omitempty"`
+}
+
+func (m *NodeListReply) Reset()         { *m = NodeListReply{} }
+func (m *NodeListReply) String() string { return proto.CompactTextString(m) }
+func (*NodeListReply) ProtoMessage()    {}
+
+func (m *NodeListReply) GetNodes() []string {
+	if m != nil {
+		return m.Nodes
+	}
+	return nil
+}
+
+type SourceListRequest struct {
+	Node string `protobuf:"bytes,1,opt,name=node,proto3" json:"node,omitempty"`
+}
+
+func (m *SourceListRequest) Reset()         { *m = SourceListRequest{} }
+func (m *SourceListRequest) String() string { return proto.CompactTextString(m) }
+func (*SourceListRequest) ProtoMessage()    {}
+
+func (m *SourceListRequest) GetNode() string {
+	if m != nil {
+		return m.Node
+	}
+	return ""
+}
+
+type SourceListReply struct {
+	Measurements []string `protobuf:"bytes,1,rep,name=measurements,proto3" json:"measurements,omitempty"`
+}
+
+func (m *SourceListReply) Reset()         { *m = SourceListReply{} }
+func (m *SourceListReply) String() string { return proto.CompactTextString(m) }
+func (*SourceListReply) ProtoMessage()    {}
+
+func (m *SourceListReply) GetMeasurements() []string {
+	if m != nil {
+		return m.Measurements
+	}
+	return nil
+}
+
+type SubscribeRequest struct {
+	Node         string   `protobuf:"bytes,1,opt,name=node,proto3" json:"node,omitempty"`
+	Measurements []string `protobuf:"bytes,2,rep,name=measurements,proto3" json:"measurements,omitempty"`
+}
+
+func (m *SubscribeRequest) Reset()         { *m = SubscribeRequest{} }
+func (m *SubscribeRequest) String() string { return proto.CompactTextString(m) }
+func (*SubscribeRequest) ProtoMessage()    {}
+
+func (m *SubscribeRequest) GetNode() string {
+	if m != nil {
+		return m.Node
+	}
+	return ""
+}
+
+func (m *SubscribeRequest) GetMeasurements() []string {
+	if m != nil {
+		return m.Measurements
+	}
+	return nil
+}
+
+type CancelRequest struct {
+	RequestId string `protobuf:"bytes,1,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+}
+
+func (m *CancelRequest) Reset()         { *m = CancelRequest{} }
+func (m *CancelRequest) String() string { return proto.CompactTextString(m) }
+func (*CancelRequest) ProtoMessage()    {}
+
+func (m *CancelRequest) GetRequestId() string {
+	if m != nil {
+		return m.RequestId
+	}
+	return ""
+}
+
+func init() {
+	proto.RegisterType((*Empty)(nil), "routingpb.Empty")
+	proto.RegisterType((*Datapoint)(nil), "routingpb.Datapoint")
+	proto.RegisterType((*WriteRequest)(nil), "routingpb.WriteRequest")
+	proto.RegisterType((*WriteReply)(nil), "routingpb.WriteReply")
+	proto.RegisterType((*ReadRequest)(nil), "routingpb.ReadRequest")
+	proto.RegisterType((*ReadReply)(nil), "routingpb.ReadReply")
+	proto.RegisterType((*NodeListReply)(nil), "routingpb.NodeListReply")
+	proto.RegisterType((*SourceListRequest)(nil), "routingpb.SourceListRequest")
+	proto.RegisterType((*SourceListReply)(nil), "routingpb.SourceListReply")
+	proto.RegisterType((*SubscribeRequest)(nil), "routingpb.SubscribeRequest")
+	proto.RegisterType((*CancelRequest)(nil), "routingpb.CancelRequest")
+}
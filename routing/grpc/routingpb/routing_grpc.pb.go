@@ -0,0 +1,277 @@
+// Hand-written to mirror the service declared in routing.proto, in the
+// style of protoc-gen-go-grpc. There is no protoc toolchain wired up in
+// this repo yet, so this is maintained by hand alongside the .proto --
+// keep it in sync when routing.proto changes.
+
+package routingpb
+
+import (
+	context "context"
+
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// RoutingServiceClient is the client API for RoutingService service.
+type RoutingServiceClient interface {
+	Write(ctx context.Context, in *WriteRequest, opts ...grpc.CallOption) (*WriteReply, error)
+	ReadImmediate(ctx context.Context, in *ReadRequest, opts ...grpc.CallOption) (*ReadReply, error)
+	NodeList(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*NodeListReply, error)
+	SourceList(ctx context.Context, in *SourceListRequest, opts ...grpc.CallOption) (*SourceListReply, error)
+	Cancel(ctx context.Context, in *CancelRequest, opts ...grpc.CallOption) (*Empty, error)
+	Subscribe(ctx context.Context, in *SubscribeRequest, opts ...grpc.CallOption) (RoutingService_SubscribeClient, error)
+}
+
+type routingServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewRoutingServiceClient(cc grpc.ClientConnInterface) RoutingServiceClient {
+	return &routingServiceClient{cc}
+}
+
+func (c *routingServiceClient) Write(ctx context.Context, in *WriteRequest, opts ...grpc.CallOption) (*WriteReply, error) {
+	out := new(WriteReply)
+	err := c.cc.Invoke(ctx, "/routingpb.RoutingService/Write", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *routingServiceClient) ReadImmediate(ctx context.Context, in *ReadRequest, opts ...grpc.CallOption) (*ReadReply, error) {
+	out := new(ReadReply)
+	err := c.cc.Invoke(ctx, "/routingpb.RoutingService/ReadImmediate", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *routingServiceClient) NodeList(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*NodeListReply, error) {
+	out := new(NodeListReply)
+	err := c.cc.Invoke(ctx, "/routingpb.RoutingService/NodeList", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *routingServiceClient) SourceList(ctx context.Context, in *SourceListRequest, opts ...grpc.CallOption) (*SourceListReply, error) {
+	out := new(SourceListReply)
+	err := c.cc.Invoke(ctx, "/routingpb.RoutingService/SourceList", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *routingServiceClient) Cancel(ctx context.Context, in *CancelRequest, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, "/routingpb.RoutingService/Cancel", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *routingServiceClient) Subscribe(ctx context.Context, in *SubscribeRequest, opts ...grpc.CallOption) (RoutingService_SubscribeClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_RoutingService_serviceDesc.Streams[0], "/routingpb.RoutingService/Subscribe", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &routingServiceSubscribeClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// RoutingService_SubscribeClient is the client side of the Subscribe
+// server-streaming RPC.
+type RoutingService_SubscribeClient interface {
+	Recv() (*ReadReply, error)
+	grpc.ClientStream
+}
+
+type routingServiceSubscribeClient struct {
+	grpc.ClientStream
+}
+
+func (x *routingServiceSubscribeClient) Recv() (*ReadReply, error) {
+	m := new(ReadReply)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// RoutingServiceServer is the server API for RoutingService service.
+type RoutingServiceServer interface {
+	Write(context.Context, *WriteRequest) (*WriteReply, error)
+	ReadImmediate(context.Context, *ReadRequest) (*ReadReply, error)
+	NodeList(context.Context, *Empty) (*NodeListReply, error)
+	SourceList(context.Context, *SourceListRequest) (*SourceListReply, error)
+	Cancel(context.Context, *CancelRequest) (*Empty, error)
+	Subscribe(*SubscribeRequest, RoutingService_SubscribeServer) error
+	mustEmbedUnimplementedRoutingServiceServer()
+}
+
+// UnimplementedRoutingServiceServer must be embedded by implementations
+// that don't provide every method, so adding an RPC doesn't break them.
+type UnimplementedRoutingServiceServer struct{}
+
+func (UnimplementedRoutingServiceServer) Write(context.Context, *WriteRequest) (*WriteReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Write not implemented")
+}
+
+func (UnimplementedRoutingServiceServer) ReadImmediate(context.Context, *ReadRequest) (*ReadReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReadImmediate not implemented")
+}
+
+func (UnimplementedRoutingServiceServer) NodeList(context.Context, *Empty) (*NodeListReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method NodeList not implemented")
+}
+
+func (UnimplementedRoutingServiceServer) SourceList(context.Context, *SourceListRequest) (*SourceListReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SourceList not implemented")
+}
+
+func (UnimplementedRoutingServiceServer) Cancel(context.Context, *CancelRequest) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Cancel not implemented")
+}
+
+func (UnimplementedRoutingServiceServer) Subscribe(*SubscribeRequest, RoutingService_SubscribeServer) error {
+	return status.Errorf(codes.Unimplemented, "method Subscribe not implemented")
+}
+
+func (UnimplementedRoutingServiceServer) mustEmbedUnimplementedRoutingServiceServer() {}
+
+func RegisterRoutingServiceServer(s grpc.ServiceRegistrar, srv RoutingServiceServer) {
+	s.RegisterService(&_RoutingService_serviceDesc, srv)
+}
+
+func _RoutingService_Write_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WriteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RoutingServiceServer).Write(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: "/routingpb.RoutingService/Write"}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RoutingServiceServer).Write(ctx, req.(*WriteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RoutingService_ReadImmediate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReadRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RoutingServiceServer).ReadImmediate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: "/routingpb.RoutingService/ReadImmediate"}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RoutingServiceServer).ReadImmediate(ctx, req.(*ReadRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RoutingService_NodeList_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RoutingServiceServer).NodeList(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: "/routingpb.RoutingService/NodeList"}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RoutingServiceServer).NodeList(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RoutingService_SourceList_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SourceListRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RoutingServiceServer).SourceList(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: "/routingpb.RoutingService/SourceList"}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RoutingServiceServer).SourceList(ctx, req.(*SourceListRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RoutingService_Cancel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CancelRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RoutingServiceServer).Cancel(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: "/routingpb.RoutingService/Cancel"}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RoutingServiceServer).Cancel(ctx, req.(*CancelRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RoutingService_Subscribe_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(RoutingServiceServer).Subscribe(m, &routingServiceSubscribeServer{stream})
+}
+
+// RoutingService_SubscribeServer is the server side of the Subscribe
+// server-streaming RPC.
+type RoutingService_SubscribeServer interface {
+	Send(*ReadReply) error
+	grpc.ServerStream
+}
+
+type routingServiceSubscribeServer struct {
+	grpc.ServerStream
+}
+
+func (x *routingServiceSubscribeServer) Send(m *ReadReply) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// RoutingService_ServiceDesc is the grpc.ServiceDesc for RoutingService
+// service. It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy).
+var _RoutingService_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "routingpb.RoutingService",
+	HandlerType: (*RoutingServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{MethodName: "Write", Handler: _RoutingService_Write_Handler},
+		{MethodName: "ReadImmediate", Handler: _RoutingService_ReadImmediate_Handler},
+		{MethodName: "NodeList", Handler: _RoutingService_NodeList_Handler},
+		{MethodName: "SourceList", Handler: _RoutingService_SourceList_Handler},
+		{MethodName: "Cancel", Handler: _RoutingService_Cancel_Handler},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Subscribe",
+			Handler:       _RoutingService_Subscribe_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "routing.proto",
+}
@@ -0,0 +1,116 @@
+package grpc
+
+import (
+	"context"
+
+	"github.com/burmanm/core/routing"
+	pb "github.com/burmanm/core/routing/grpc/routingpb"
+)
+
+// Store is any routing.Datastore backend: InMemoryStore, BoltStore,
+// ConsulStore, or a future implementation.
+type Store = routing.Datastore
+
+// Server adapts a Store to the RoutingService gRPC service.
+type Server struct {
+	pb.UnimplementedRoutingServiceServer
+	store Store
+}
+
+func NewServer(store Store) *Server {
+	return &Server{store: store}
+}
+
+func (s *Server) Write(ctx context.Context, req *pb.WriteRequest) (*pb.WriteReply, error) {
+	err, reply := s.store.Write(&routing.Write{
+		Node:       req.Node,
+		Datapoints: fromDatapoints(req.Datapoints),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &pb.WriteReply{RequestId: reply.RequestId}, nil
+}
+
+func (s *Server) ReadImmediate(ctx context.Context, req *pb.ReadRequest) (*pb.ReadReply, error) {
+	replyChan := make(chan routing.Reply, 1)
+	if err, _ := s.store.ReadImmediate(&routing.Request{
+		Node:         req.Node,
+		Measurements: req.Measurements,
+		ReplyChan:    replyChan,
+	}); err != nil {
+		return nil, err
+	}
+	return toReadReply(<-replyChan), nil
+}
+
+func (s *Server) NodeList(ctx context.Context, _ *pb.Empty) (*pb.NodeListReply, error) {
+	return &pb.NodeListReply{Nodes: s.store.NodeList()}, nil
+}
+
+func (s *Server) SourceList(ctx context.Context, req *pb.SourceListRequest) (*pb.SourceListReply, error) {
+	err, measurements := s.store.SourceList(req.Node)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.SourceListReply{Measurements: measurements}, nil
+}
+
+func (s *Server) Cancel(ctx context.Context, req *pb.CancelRequest) (*pb.Empty, error) {
+	if err := s.store.Cancel(req.RequestId); err != nil {
+		return nil, err
+	}
+	return &pb.Empty{}, nil
+}
+
+// Subscribe creates a Tracking entry on the store and forwards every Reply
+// delivered on its reply channel to the stream, until the client
+// disconnects, at which point the tracking is cancelled.
+func (s *Server) Subscribe(req *pb.SubscribeRequest, stream pb.RoutingService_SubscribeServer) error {
+	replyChan := make(chan routing.Reply, 16)
+	err, initial := s.store.Subscribe(&routing.Request{
+		Node:         req.Node,
+		Measurements: req.Measurements,
+		ReplyChan:    replyChan,
+	})
+	if err != nil {
+		return err
+	}
+	defer s.store.Cancel(initial.RequestId)
+
+	ctx := stream.Context()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case r := <-replyChan:
+			if err := stream.Send(toReadReply(r)); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func fromDatapoints(in []*pb.Datapoint) []routing.Data {
+	out := make([]routing.Data, 0, len(in))
+	for _, d := range in {
+		out = append(out, routing.Data{Measurement: d.Measurement, Value: d.Value, Timestamp: d.Timestamp})
+	}
+	return out
+}
+
+func toDatapoints(in []routing.Data) []*pb.Datapoint {
+	out := make([]*pb.Datapoint, 0, len(in))
+	for _, d := range in {
+		out = append(out, &pb.Datapoint{Measurement: d.Measurement, Value: d.Value, Timestamp: d.Timestamp})
+	}
+	return out
+}
+
+func toReadReply(r routing.Reply) *pb.ReadReply {
+	return &pb.ReadReply{
+		RequestId:  r.RequestId,
+		Node:       r.Node,
+		Datapoints: toDatapoints(r.Datapoints),
+	}
+}
@@ -0,0 +1,62 @@
+package routing
+
+/*
+  history is a fixed-size ring buffer of Data samples for a single Key,
+  kept in ascending timestamp order. Once full, appending drops the
+  oldest sample.
+*/
+type history struct {
+	samples []Data
+	start   int
+	count   int
+}
+
+func newHistory(capacity int) *history {
+	return &history{samples: make([]Data, capacity)}
+}
+
+// newest returns the sample with the latest timestamp, if any.
+func (h *history) newest() (Data, bool) {
+	if h.count == 0 {
+		return Data{}, false
+	}
+	return h.samples[(h.start+h.count-1)%len(h.samples)], true
+}
+
+// append inserts d in timestamp order, so a sample accepted within the
+// out-of-order slack still lands before any newer ones.
+func (h *history) append(d Data) {
+	capacity := len(h.samples)
+	if capacity == 0 {
+		return
+	}
+	if h.count == capacity {
+		h.start = (h.start + 1) % capacity
+		h.count--
+	}
+	i := h.count
+	for i > 0 && h.samples[(h.start+i-1)%capacity].Timestamp > d.Timestamp {
+		h.samples[(h.start+i)%capacity] = h.samples[(h.start+i-1)%capacity]
+		i--
+	}
+	h.samples[(h.start+i)%capacity] = d
+	h.count++
+}
+
+// ascending returns the buffered samples oldest-first.
+func (h *history) ascending() []Data {
+	out := make([]Data, 0, h.count)
+	for i := 0; i < h.count; i++ {
+		out = append(out, h.samples[(h.start+i)%len(h.samples)])
+	}
+	return out
+}
+
+// trimOlderThan drops samples with Timestamp older than cutoff from the
+// front of the buffer.
+func (h *history) trimOlderThan(cutoff int64) {
+	for h.count > 0 && h.samples[h.start].Timestamp < cutoff {
+		h.start = (h.start + 1) % len(h.samples)
+		h.count--
+	}
+}
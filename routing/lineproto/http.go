@@ -0,0 +1,77 @@
+package lineproto
+
+import (
+	"bufio"
+	"fmt"
+	"net/http"
+
+	"github.com/burmanm/core/routing"
+)
+
+// appendWriter is satisfied by any Datastore that also supports
+// WriteAppend, e.g. *routing.InMemoryStore.
+type appendWriter interface {
+	WriteAppend(w *routing.Write) (error, routing.Reply)
+}
+
+/*
+  Handler is an http.Handler for POST /write accepting a batch of
+  InfluxDB line protocol in the request body, one point per line.
+*/
+type Handler struct {
+	Store routing.Datastore
+
+	// NodeTag is the tag mapped to Write.Node; DefaultNodeTag if empty.
+	NodeTag string
+
+	// Append, when true, applies each write with WriteAppend instead of
+	// Write, skipping the delete-absent-measurements pass for streaming
+	// ingestion. Store must implement WriteAppend.
+	Append bool
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	defer req.Body.Close()
+
+	var lines []*Line
+	scanner := bufio.NewScanner(req.Body)
+	for scanner.Scan() {
+		line, err := Parse(scanner.Text())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if line != nil {
+			lines = append(lines, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	for _, write := range ToWrites(lines, h.NodeTag) {
+		if err := h.apply(write); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) apply(write *routing.Write) error {
+	if h.Append {
+		appender, ok := h.Store.(appendWriter)
+		if !ok {
+			return fmt.Errorf("lineproto: store %T does not support WriteAppend", h.Store)
+		}
+		err, _ := appender.WriteAppend(write)
+		return err
+	}
+	err, _ := h.Store.Write(write)
+	return err
+}
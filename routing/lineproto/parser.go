@@ -0,0 +1,144 @@
+/*
+  Package lineproto parses InfluxDB line protocol and maps it onto
+  routing.Write batches:
+
+    measurement,tag1=v1 field1=1.2,field2=3 <unix-ns>
+
+  The host (or a configurable) tag becomes Write.Node, and each field
+  becomes a routing.Data with Measurement = "<measurement>.<field>".
+*/
+package lineproto
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultNodeTag is the tag mapped to Write.Node when none is given to
+// ToWrites.
+const DefaultNodeTag = "host"
+
+// Line is one parsed line-protocol point.
+type Line struct {
+	Measurement string
+	Tags        map[string]string
+	Fields      map[string]string
+	Timestamp   int64 // seconds
+}
+
+// Parse parses a single line. A blank line or a comment line (starting
+// with '#') returns a nil *Line and no error.
+func Parse(line string) (*Line, error) {
+	line = strings.TrimSpace(line)
+	if line == "" || strings.HasPrefix(line, "#") {
+		return nil, nil
+	}
+
+	sections := splitUnescaped(line, ' ')
+	if len(sections) < 2 {
+		return nil, fmt.Errorf("lineproto: malformed line %q", line)
+	}
+
+	measurement, tags, err := parseMeasurementAndTags(sections[0])
+	if err != nil {
+		return nil, fmt.Errorf("lineproto: %w in %q", err, line)
+	}
+
+	fields, err := parseFields(sections[1])
+	if err != nil {
+		return nil, fmt.Errorf("lineproto: %w in %q", err, line)
+	}
+
+	var timestamp int64
+	if len(sections) >= 3 {
+		ns, err := strconv.ParseInt(sections[2], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("lineproto: invalid timestamp %q: %w", sections[2], err)
+		}
+		timestamp = ns / int64(time.Second)
+	}
+
+	return &Line{Measurement: measurement, Tags: tags, Fields: fields, Timestamp: timestamp}, nil
+}
+
+func parseMeasurementAndTags(section string) (string, map[string]string, error) {
+	parts := splitUnescaped(section, ',')
+	measurement := unescape(parts[0])
+	if measurement == "" {
+		return "", nil, fmt.Errorf("missing measurement")
+	}
+
+	tags := make(map[string]string, len(parts)-1)
+	for _, pair := range parts[1:] {
+		k, v, err := splitKV(pair)
+		if err != nil {
+			return "", nil, fmt.Errorf("bad tag %q: %w", pair, err)
+		}
+		tags[k] = v
+	}
+	return measurement, tags, nil
+}
+
+func parseFields(section string) (map[string]string, error) {
+	parts := splitUnescaped(section, ',')
+	fields := make(map[string]string, len(parts))
+	for _, pair := range parts {
+		k, v, err := splitKV(pair)
+		if err != nil {
+			return nil, fmt.Errorf("bad field %q: %w", pair, err)
+		}
+		// Integer fields carry a trailing "i" suffix (e.g. 3i); the
+		// value is still taken as the field's plain string form.
+		fields[k] = strings.TrimSuffix(v, "i")
+	}
+	return fields, nil
+}
+
+func splitKV(pair string) (string, string, error) {
+	idx := strings.IndexByte(pair, '=')
+	if idx < 0 {
+		return "", "", fmt.Errorf("expected key=value")
+	}
+	return unescape(pair[:idx]), unescape(pair[idx+1:]), nil
+}
+
+// splitUnescaped splits s on sep, treating a backslash-escaped sep as a
+// literal character rather than a delimiter.
+func splitUnescaped(s string, sep byte) []string {
+	var parts []string
+	var cur strings.Builder
+	escaped := false
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case escaped:
+			cur.WriteByte(c)
+			escaped = false
+		case c == '\\':
+			escaped = true
+		case c == sep:
+			parts = append(parts, cur.String())
+			cur.Reset()
+		default:
+			cur.WriteByte(c)
+		}
+	}
+	parts = append(parts, cur.String())
+	return parts
+}
+
+func unescape(s string) string {
+	if !strings.ContainsRune(s, '\\') {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) {
+			i++
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
@@ -0,0 +1,30 @@
+package lineproto
+
+import "testing"
+
+func BenchmarkParse(b *testing.B) {
+	line := "cpu,host=web-1,region=us-east usage=0.5,count=3i,idle=99.1 1577836800000000000"
+	b.ReportAllocs()
+	b.SetBytes(int64(len(line)))
+	for i := 0; i < b.N; i++ {
+		if _, err := Parse(line); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkToWrites(b *testing.B) {
+	lines := make([]*Line, 0, 100)
+	for i := 0; i < 100; i++ {
+		lines = append(lines, &Line{
+			Measurement: "cpu",
+			Tags:        map[string]string{"host": "web-1"},
+			Fields:      map[string]string{"usage": "0.5"},
+			Timestamp:   100,
+		})
+	}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		ToWrites(lines, "")
+	}
+}
@@ -0,0 +1,25 @@
+package lineproto
+
+import "testing"
+
+func FuzzParse(f *testing.F) {
+	seeds := []string{
+		"cpu,host=web-1 usage=0.5,count=3i 1577836800000000000",
+		"cpu,host=web-1 usage=0.5",
+		"",
+		"# comment",
+		`cpu,host=web\ 1 usage=0.5`,
+		"cpu usage=0.5,count=3i",
+		"cpu,host=web-1",
+		",",
+		"=",
+	}
+	for _, s := range seeds {
+		f.Add(s)
+	}
+
+	f.Fuzz(func(t *testing.T, line string) {
+		// Parse must never panic; any rejection should surface as an error.
+		_, _ = Parse(line)
+	})
+}
@@ -0,0 +1,56 @@
+package lineproto
+
+import "testing"
+
+func TestParse(t *testing.T) {
+	l, err := Parse("cpu,host=web-1 usage=0.5,count=3i 1577836800000000000")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if l.Measurement != "cpu" {
+		t.Errorf("expected measurement cpu, got %q", l.Measurement)
+	}
+	if l.Tags["host"] != "web-1" {
+		t.Errorf("expected host tag web-1, got %q", l.Tags["host"])
+	}
+	if l.Fields["usage"] != "0.5" || l.Fields["count"] != "3" {
+		t.Errorf("unexpected fields: %+v", l.Fields)
+	}
+	if l.Timestamp != 1577836800 {
+		t.Errorf("expected timestamp 1577836800, got %d", l.Timestamp)
+	}
+}
+
+func TestParseBlankAndComment(t *testing.T) {
+	for _, line := range []string{"", "   ", "# a comment"} {
+		l, err := Parse(line)
+		if err != nil || l != nil {
+			t.Errorf("Parse(%q) = %v, %v; want nil, nil", line, l, err)
+		}
+	}
+}
+
+func TestParseMissingFields(t *testing.T) {
+	if _, err := Parse("cpu,host=web-1"); err == nil {
+		t.Error("expected error for line with no fields section")
+	}
+}
+
+func TestToWrites(t *testing.T) {
+	lines := []*Line{
+		{Measurement: "cpu", Tags: map[string]string{"host": "web-1"}, Fields: map[string]string{"usage": "0.5"}, Timestamp: 100},
+		{Measurement: "mem", Tags: map[string]string{"host": "web-1"}, Fields: map[string]string{"used": "1024"}, Timestamp: 100},
+		{Measurement: "cpu", Tags: map[string]string{"host": "web-2"}, Fields: map[string]string{"usage": "0.8"}, Timestamp: 100},
+	}
+
+	writes := ToWrites(lines, "")
+	if len(writes) != 2 {
+		t.Fatalf("expected one write per node, got %d", len(writes))
+	}
+	if writes[0].Node != "web-1" || len(writes[0].Datapoints) != 2 {
+		t.Errorf("unexpected first write: %+v", writes[0])
+	}
+	if writes[1].Node != "web-2" || len(writes[1].Datapoints) != 1 {
+		t.Errorf("unexpected second write: %+v", writes[1])
+	}
+}
@@ -0,0 +1,39 @@
+package lineproto
+
+import "github.com/burmanm/core/routing"
+
+// ToWrites groups parsed lines into one *routing.Write per node (keyed by
+// the nodeTag tag, DefaultNodeTag if empty), preserving each line's order
+// within its node's batch. Batching this way lets a single HTTP request
+// be applied as one routing.Write per node, keeping the "delete
+// measurements not present" semantics intact for non-streaming ingestion.
+func ToWrites(lines []*Line, nodeTag string) []*routing.Write {
+	if nodeTag == "" {
+		nodeTag = DefaultNodeTag
+	}
+
+	byNode := make(map[string][]routing.Data)
+	order := make([]string, 0)
+	for _, l := range lines {
+		if l == nil {
+			continue
+		}
+		node := l.Tags[nodeTag]
+		if _, found := byNode[node]; !found {
+			order = append(order, node)
+		}
+		for field, value := range l.Fields {
+			byNode[node] = append(byNode[node], routing.Data{
+				Measurement: l.Measurement + "." + field,
+				Value:       value,
+				Timestamp:   l.Timestamp,
+			})
+		}
+	}
+
+	writes := make([]*routing.Write, 0, len(order))
+	for _, node := range order {
+		writes = append(writes, &routing.Write{Node: node, Datapoints: byNode[node]})
+	}
+	return writes
+}
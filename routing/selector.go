@@ -0,0 +1,89 @@
+package routing
+
+import "path"
+
+/*
+  SelectorTracking is like Tracking, but matches Keys by glob pattern
+  (as understood by path.Match: *, ?, [...]) instead of an exact list,
+  so one subscription can span multiple nodes and/or measurements, e.g.
+  Node: "web-*", Measurements: []string{"cpu.*", "mem.used"}.
+*/
+type SelectorTracking struct {
+	requestId           string
+	reply               chan Reply
+	nodePattern         string
+	measurementPatterns []string
+}
+
+func (t *SelectorTracking) matches(k Key) bool {
+	if ok, _ := path.Match(t.nodePattern, k.Node); !ok {
+		return false
+	}
+	for _, p := range t.measurementPatterns {
+		if ok, _ := path.Match(p, k.Measurement); ok {
+			return true
+		}
+	}
+	return false
+}
+
+/*
+  SubscribeSelector is like Subscribe, but r.Node and r.Measurements are
+  glob patterns rather than an exact node and measurement list. A node or
+  measurement created after the subscription is made still joins it: every
+  Write evaluates new Keys against outstanding selector trackers, and that
+  first publish doubles as the initial snapshot for the newly-matching key.
+*/
+func (m *InMemoryStore) SubscribeSelector(r *Request) (error, Reply) {
+	rId := m.requestId()
+
+	t := &SelectorTracking{
+		requestId:           rId,
+		reply:               r.ReplyChan,
+		nodePattern:         r.Node,
+		measurementPatterns: append([]string(nil), r.Measurements...),
+	}
+
+	m.selectorMu.Lock()
+	m.selectors[rId] = t
+	m.selectorMu.Unlock()
+
+	return nil, Reply{RequestId: rId}
+}
+
+// Match returns the Keys currently in the store that match selector's
+// Node and Measurements patterns, so a caller can prime its own state
+// before streaming further updates from SubscribeSelector.
+func (m *InMemoryStore) Match(selector *Request) []Key {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var keys []Key
+	for node, measurements := range m.datastore {
+		if ok, _ := path.Match(selector.Node, node); !ok {
+			continue
+		}
+		for measurement := range measurements {
+			for _, p := range selector.Measurements {
+				if ok, _ := path.Match(p, measurement); ok {
+					keys = append(keys, Key{Node: node, Measurement: measurement})
+					break
+				}
+			}
+		}
+	}
+	return keys
+}
+
+func (m *InMemoryStore) publishSelectors(key Key, value string, timestamp int64) {
+	m.selectorMu.RLock()
+	defer m.selectorMu.RUnlock()
+
+	for _, t := range m.selectors {
+		if !t.matches(key) {
+			continue
+		}
+		d := Data{Measurement: key.Measurement, Value: value, Timestamp: timestamp}
+		t.reply <- Reply{RequestId: t.requestId, Node: key.Node, Datapoints: []Data{d}}
+	}
+}
@@ -0,0 +1,73 @@
+package routing
+
+import "testing"
+
+func TestSubscribeSelectorMatchesExistingAndFutureKeys(t *testing.T) {
+	m := NewInMemoryStore()
+	write(t, m, "web-1", "cpu.load", "1", 100)
+
+	replyChan := make(chan Reply, 10)
+	err, reply := m.SubscribeSelector(&Request{
+		Node:         "web-*",
+		Measurements: []string{"cpu.*", "mem.used"},
+		ReplyChan:    replyChan,
+	})
+	if err != nil {
+		t.Fatalf("SubscribeSelector failed: %v", err)
+	}
+	if reply.RequestId == "" {
+		t.Fatal("expected a request id")
+	}
+
+	// A matching write on an already-tracked node.
+	write(t, m, "web-1", "cpu.load", "2", 101)
+	select {
+	case r := <-replyChan:
+		if r.Datapoints[0].Value != "2" {
+			t.Errorf("expected value 2, got %q", r.Datapoints[0].Value)
+		}
+	default:
+		t.Fatal("expected a reply for matching write on existing node")
+	}
+
+	// A write to a brand new node matching the selector joins retroactively.
+	write(t, m, "web-2", "mem.used", "512", 102)
+	select {
+	case r := <-replyChan:
+		if r.Node != "web-2" || r.Datapoints[0].Value != "512" {
+			t.Errorf("expected web-2/512, got %+v", r)
+		}
+	default:
+		t.Fatal("expected a reply for new node matching selector")
+	}
+
+	// A write that doesn't match the selector should not be delivered.
+	write(t, m, "db-1", "cpu.load", "9", 103)
+	select {
+	case r := <-replyChan:
+		t.Fatalf("did not expect a reply for non-matching node, got %+v", r)
+	default:
+	}
+
+	if err := m.Cancel(reply.RequestId); err != nil {
+		t.Fatalf("Cancel failed: %v", err)
+	}
+	write(t, m, "web-1", "cpu.load", "3", 104)
+	select {
+	case r := <-replyChan:
+		t.Fatalf("did not expect a reply after cancel, got %+v", r)
+	default:
+	}
+}
+
+func TestMatchReturnsCurrentlyMatchingKeys(t *testing.T) {
+	m := NewInMemoryStore()
+	write(t, m, "web-1", "cpu.load", "1", 100)
+	write(t, m, "web-2", "mem.used", "2", 100)
+	write(t, m, "db-1", "cpu.load", "3", 100)
+
+	keys := m.Match(&Request{Node: "web-*", Measurements: []string{"cpu.*", "mem.used"}})
+	if len(keys) != 2 {
+		t.Fatalf("expected 2 matching keys, got %d: %+v", len(keys), keys)
+	}
+}
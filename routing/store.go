@@ -0,0 +1,18 @@
+package routing
+
+/*
+  Datastore is the interface every backend must satisfy. InMemoryStore is
+  the default, in-process implementation; BoltStore and ConsulStore persist
+  the node/measurement state across restarts and, in the Consul case,
+  across a cluster.
+*/
+type Datastore interface {
+	Subscribe(r *Request) (error, Reply)
+	ReadImmediate(r *Request) (error, Reply)
+	Write(w *Write) (error, Reply)
+	Cancel(requestId string) error
+	NodeList() []string
+	SourceList(node string) (error, []string)
+}
+
+var _ Datastore = (*InMemoryStore)(nil)
@@ -0,0 +1,52 @@
+package routing
+
+/*
+  Data is a single measurement sample for a node.
+*/
+type Data struct {
+	Measurement string
+	Value       string
+	Timestamp   int64
+}
+
+/*
+  Request describes a query against the store: either an immediate read,
+  a subscription, or (with From/To set) a historical range read.
+*/
+type Request struct {
+	Node         string
+	Measurements []string
+	ReplyChan    chan Reply
+
+	// From, To and Limit are only used by ReadRange: From/To bound the
+	// query to samples with Timestamp in [From, To], and Limit caps the
+	// number of samples returned per measurement (0 means unlimited).
+	From, To int64
+	Limit    int
+}
+
+/*
+  Reply carries datapoints back to a caller, either as a direct return
+  value or over a Request's ReplyChan.
+*/
+type Reply struct {
+	RequestId  string
+	Node       string
+	Datapoints []Data
+
+	// From, To and Limit mirror the Request that produced this Reply so
+	// range-query callers can keep paging without holding onto the
+	// original Request.
+	From, To int64
+	Limit    int
+}
+
+/*
+  Write is a batch of datapoints for a single node. Any measurement
+  previously stored for the node that is absent from Datapoints is
+  deleted.
+*/
+type Write struct {
+	Node       string
+	Datapoints []Data
+}